@@ -0,0 +1,88 @@
+package progress
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMultiCounterAggregatesAndReportsPerStream(t *testing.T) {
+	mc := NewMultiCounter()
+	r1 := mc.NewReader("file1", strings.NewReader(strings.Repeat("a", 30)))
+	r2 := mc.NewReader("file2", strings.NewReader(strings.Repeat("b", 70)))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); io.Copy(io.Discard, r1) }()
+	go func() { defer wg.Done(); io.Copy(io.Discard, r2) }()
+	wg.Wait()
+
+	if got := mc.N(); got != 100 {
+		t.Fatalf("N() = %d, want 100", got)
+	}
+
+	per := mc.PerStream()
+	if len(per) != 2 {
+		t.Fatalf("PerStream() returned %d streams, want 2", len(per))
+	}
+	want := map[string]int64{"file1": 30, "file2": 70}
+	for _, sp := range per {
+		if sp.N != want[sp.Label] {
+			t.Fatalf("stream %q: N = %d, want %d", sp.Label, sp.N, want[sp.Label])
+		}
+	}
+}
+
+// TestMultiCounterAddConcurrentWithN exercises registering new streams
+// concurrently with reads of N/PerStream, which rely on the lock-free
+// atomic.Value snapshot rather than a read lock.
+func TestMultiCounterAddConcurrentWithN(t *testing.T) {
+	mc := NewMultiCounter()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			mc.NewReader("stream", strings.NewReader("x"))
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		_ = mc.N()
+		_ = mc.PerStream()
+	}
+	wg.Wait()
+
+	if got := len(mc.PerStream()); got != 20 {
+		t.Fatalf("PerStream() returned %d streams, want 20", got)
+	}
+}
+
+// TestMultiCounterUnknownSizeCompletesOnceAllStreamsAreDone checks that an
+// unknown-size NewTicker driven by a MultiCounter becomes Complete once
+// every registered Reader has hit EOF, the same way it would for a single
+// Reader.
+func TestMultiCounterUnknownSizeCompletesOnceAllStreamsAreDone(t *testing.T) {
+	mc := NewMultiCounter()
+	r1 := mc.NewReader("file1", strings.NewReader("aaa"))
+	r2 := mc.NewReader("file2", strings.NewReader("bb"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := NewTicker(ctx, mc, 0, 2*time.Millisecond)
+
+	go io.Copy(io.Discard, r1)
+	go io.Copy(io.Discard, r2)
+
+	var last Progress
+	for p := range ch {
+		last = p
+	}
+	if !last.Complete() {
+		t.Fatalf("expected the ticker to become Complete once all streams hit EOF")
+	}
+}
@@ -1,36 +1,40 @@
 // Package progress provides io.Reader and io.Writer with progress and remaining time estimation.
-//  ctx := context.Background()
 //
-//  // get a reader and the total expected number of bytes
-//  s := `Now that's what I call progress`
-//  size := len(s)
-//  r := progress.NewReader(strings.NewReader(s))
+//	ctx := context.Background()
 //
-//  // Start a goroutine printing progress
-//  go func(){
-//  	defer log.Printf("done")
-//  	interval := 1 * time.Second
-//  	progressChan := progress.NewTicker(ctx, r, size, interval)
-//  	for {
-//  		select {
-//  		case progress, ok := <-progressChan:
-//  			if !ok {
-//  				// if ok is false, the process is finished
-//  				return
-//  			}
-//  			log.Printf("about %v remaining...", progress.Remaining())
-//  		}
-//  	}
-//  }()
+//	// get a reader and the total expected number of bytes
+//	s := `Now that's what I call progress`
+//	size := len(s)
+//	r := progress.NewReader(strings.NewReader(s))
 //
-//  // use the Reader as normal
-//  if _, err := io.Copy(dest, r); err != nil {
-//  	log.Fatalln(err)
-//  }
+//	// Start a goroutine printing progress
+//	go func(){
+//		defer log.Printf("done")
+//		interval := 1 * time.Second
+//		progressChan := progress.NewTicker(ctx, r, size, interval)
+//		for {
+//			select {
+//			case progress, ok := <-progressChan:
+//				if !ok {
+//					// if ok is false, the process is finished
+//					return
+//				}
+//				log.Printf("about %v remaining...", progress.Remaining())
+//			}
+//		}
+//	}()
+//
+//	// use the Reader as normal
+//	if _, err := io.Copy(dest, r); err != nil {
+//		log.Fatalln(err)
+//	}
 package progress
 
 import (
 	"context"
+	"io"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -44,11 +48,189 @@ type Counter interface {
 	N() int64
 }
 
+// Doner is implemented by a Counter that can report whether it has
+// finished, such as a Reader that has reached EOF. NewTicker uses this
+// to decide when an unknown-size operation (size <= 0) is Complete.
+type Doner interface {
+	// Done gets whether the counter has finished.
+	Done() bool
+}
+
+// Reader counts the bytes read through it.
+type Reader struct {
+	r    io.Reader
+	n    int64
+	done int32
+}
+
+// NewReader gets a Reader that wraps r, counting the bytes read through it.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// Read reads from the underlying io.Reader, tracking the number of bytes
+// read.
+func (r *Reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	atomic.AddInt64(&r.n, int64(n))
+	if err == io.EOF {
+		atomic.StoreInt32(&r.done, 1)
+	}
+	return n, err
+}
+
+// N gets the current count of bytes read so far. It is safe to call
+// from any goroutine, including while Read is in progress.
+func (r *Reader) N() int64 {
+	return atomic.LoadInt64(&r.n)
+}
+
+// Done gets whether the underlying io.Reader has returned io.EOF.
+func (r *Reader) Done() bool {
+	return atomic.LoadInt32(&r.done) == 1
+}
+
+// Writer counts the bytes written through it.
+type Writer struct {
+	w io.Writer
+	n int64
+}
+
+// NewWriter gets a Writer that wraps w, counting the bytes written
+// through it. Unlike Reader, Writer does not implement Doner: a writer
+// has no equivalent of EOF to signal it has finished. This means a
+// NewTicker/NewUpdater run against a Writer with an unknown size
+// (size <= 0) never becomes Complete on its own; the caller must stop it
+// itself, via ctx cancellation or Updater.Done.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write writes to the underlying io.Writer, tracking the number of bytes
+// written.
+func (w *Writer) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	atomic.AddInt64(&w.n, int64(n))
+	return n, err
+}
+
+// N gets the current count of bytes written so far. It is safe to call
+// from any goroutine, including while Write is in progress.
+func (w *Writer) N() int64 {
+	return atomic.LoadInt64(&w.n)
+}
+
+// StreamProgress is one named stream's contribution to a MultiCounter, as
+// captured in a Progress snapshot. See Progress.PerStream.
+type StreamProgress struct {
+	// Label identifies the stream, as given to MultiCounter.NewReader or
+	// MultiCounter.NewWriter.
+	Label string
+	// N is the number of bytes read or written through the stream so far.
+	N int64
+}
+
+// namedCounter pairs a Counter with the label it was registered under.
+type namedCounter struct {
+	label   string
+	counter Counter
+}
+
+// MultiCounter aggregates several Counters into one, so a single
+// NewTicker/NewUpdater can report combined progress across parallel
+// transfers while still exposing each stream's contribution via
+// Progress.PerStream.
+type MultiCounter struct {
+	mu      sync.Mutex
+	streams atomic.Value // []*namedCounter
+}
+
+// NewMultiCounter gets a MultiCounter with no streams registered.
+func NewMultiCounter() *MultiCounter {
+	mc := &MultiCounter{}
+	mc.streams.Store([]*namedCounter{})
+	return mc
+}
+
+// add registers counter under label. It is safe to call concurrently with
+// itself (calls are serialised by mc.mu) and with N and PerStream.
+func (mc *MultiCounter) add(label string, counter Counter) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	old := mc.streams.Load().([]*namedCounter)
+	next := make([]*namedCounter, len(old)+1)
+	copy(next, old)
+	next[len(old)] = &namedCounter{label: label, counter: counter}
+	mc.streams.Store(next)
+}
+
+// NewReader gets a Reader that wraps r, registering it with mc under label
+// so its bytes count toward mc's total and are reported separately via
+// PerStream.
+func (mc *MultiCounter) NewReader(label string, r io.Reader) *Reader {
+	rd := NewReader(r)
+	mc.add(label, rd)
+	return rd
+}
+
+// NewWriter gets a Writer that wraps w, registering it with mc under label
+// so its bytes count toward mc's total and are reported separately via
+// PerStream.
+func (mc *MultiCounter) NewWriter(label string, w io.Writer) *Writer {
+	wr := NewWriter(w)
+	mc.add(label, wr)
+	return wr
+}
+
+// N gets the sum of N() across all streams registered with mc.
+func (mc *MultiCounter) N() int64 {
+	var total int64
+	for _, s := range mc.streams.Load().([]*namedCounter) {
+		total += s.counter.N()
+	}
+	return total
+}
+
+// PerStream gets a snapshot of each registered stream's current byte
+// count, in registration order.
+func (mc *MultiCounter) PerStream() []StreamProgress {
+	streams := mc.streams.Load().([]*namedCounter)
+	out := make([]StreamProgress, len(streams))
+	for i, s := range streams {
+		out[i] = StreamProgress{Label: s.label, N: s.counter.N()}
+	}
+	return out
+}
+
+// Done implements Doner: mc reports itself done once at least one stream
+// has been registered and every registered stream both implements Doner
+// and reports itself Done. This lets an unknown-size NewTicker/NewUpdater
+// driven by mc become Complete once every parallel transfer has finished,
+// the same way a single Reader does.
+func (mc *MultiCounter) Done() bool {
+	streams := mc.streams.Load().([]*namedCounter)
+	if len(streams) == 0 {
+		return false
+	}
+	for _, s := range streams {
+		doner, ok := s.counter.(Doner)
+		if !ok || !doner.Done() {
+			return false
+		}
+	}
+	return true
+}
+
 // Progress represents a moment of progress.
 type Progress struct {
 	n         float64
 	size      float64
 	estimated time.Time
+	speed     float64
+	speedAvg  float64
+	unknown   bool
+	done      bool
+	perStream []StreamProgress
 }
 
 // N gets the total number of bytes read or written
@@ -69,12 +251,21 @@ func (p Progress) Started() bool {
 }
 
 // Complete gets whether the operation is complete or not.
+// If the total size is unknown, this is only true once the counter
+// reports itself Done (see Doner).
 func (p Progress) Complete() bool {
+	if p.unknown {
+		return p.done
+	}
 	return p.n >= p.size
 }
 
 // Percent calculates the percentage complete.
+// If the total size is unknown, this returns -1.
 func (p Progress) Percent() float64 {
+	if p.unknown {
+		return -1
+	}
 	if p.n == 0 {
 		return 0
 	}
@@ -86,56 +277,253 @@ func (p Progress) Percent() float64 {
 
 // Remaining gets the amount of time until the operation is
 // expected to be finished. Use Estimated to get a fixed completion time.
+// If the total size is unknown, this is always zero.
 func (p Progress) Remaining() time.Duration {
+	if p.unknown {
+		return 0
+	}
 	return p.estimated.Sub(time.Now())
 }
 
 // Estimated gets the time at which the operation is expected
 // to finish. Use Reamining to get a Duration.
+// If the total size is unknown, this is always the zero time.
 func (p Progress) Estimated() time.Time {
+	if p.unknown {
+		return time.Time{}
+	}
 	return p.estimated
 }
 
+// Speed gets the instantaneous transfer rate in bytes/sec, based on a
+// sliding window of the most recent samples. Use SpeedAvg for the rate
+// since the operation started.
+func (p Progress) Speed() int64 {
+	return int64(p.speed)
+}
+
+// SpeedAvg gets the average transfer rate in bytes/sec since the
+// operation started.
+func (p Progress) SpeedAvg() int64 {
+	return int64(p.speedAvg)
+}
+
+// PerStream gets the per-stream breakdown of this Progress, populated when
+// the Counter passed to NewTicker/NewUpdater is a *MultiCounter. It is nil
+// otherwise.
+func (p Progress) PerStream() []StreamProgress {
+	return p.perStream
+}
+
+// defaultWindow is the number of recent samples NewTicker keeps to compute
+// instantaneous speed.
+const defaultWindow = 10
+
+// defaultAlpha is the smoothing factor used for the EWMA of recent speed
+// samples that drives Remaining/Estimated.
+const defaultAlpha = 0.2
+
+// minInterval is the tick interval NewTicker/NewUpdater use in place of a
+// non-positive d, which would otherwise be handed to time.NewTicker as-is.
+// A positive d, however small, is used as given.
+const minInterval = 10 * time.Millisecond
+
+// Options configures the behaviour of NewTickerOptions.
+type Options struct {
+	// Window is the number of recent (time, N) samples kept to compute
+	// instantaneous Speed. Zero selects the default of 10.
+	Window int
+	// Alpha is the smoothing factor (0, 1] applied to the EWMA of recent
+	// speed samples used to compute Remaining/Estimated. Zero selects the
+	// default of 0.2.
+	Alpha float64
+}
+
+// sample records the counter value at a point in time, used to compute
+// instantaneous speed over a sliding window.
+type sample struct {
+	t time.Time
+	n float64
+}
+
+// Updater periodically computes Progress from a Counter and delivers it to
+// a callback, until the operation completes or Done is called.
+type Updater struct {
+	stop    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// NewUpdater starts a goroutine that computes Progress from counter every
+// d, passing each one to cb, until the operation is complete or Done is
+// called. The size is the total number of expected bytes being read or
+// written; if size is <= 0 the total is treated as unknown (see NewTicker),
+// and, per NewTicker, a Writer never reports itself Complete in that mode.
+func NewUpdater(counter Counter, size int64, d time.Duration, cb func(Progress)) *Updater {
+	return NewUpdaterOptions(counter, size, d, cb, Options{})
+}
+
+// NewUpdaterOptions is like NewUpdater but allows the sliding window size
+// and EWMA smoothing factor used for Speed and Remaining/Estimated to be
+// configured.
+func NewUpdaterOptions(counter Counter, size int64, d time.Duration, cb func(Progress), opts Options) *Updater {
+	window := opts.Window
+	if window <= 0 {
+		window = defaultWindow
+	}
+	alpha := opts.Alpha
+	if alpha <= 0 {
+		alpha = defaultAlpha
+	}
+	u := &Updater{
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go u.run(counter, size, d, window, alpha, cb)
+	return u
+}
+
+// Done stops the updater, delivering one final Progress value to the
+// callback first so a consumer always sees a last tick even if the
+// operation never reached completion (e.g. the caller is shutting down
+// early). It blocks until the updater's goroutine has exited.
+func (u *Updater) Done() {
+	u.once.Do(func() { close(u.stop) })
+	<-u.stopped
+}
+
+// run computes Progress from counter every d and passes it to cb, until
+// either the operation completes or stop is closed, at which point it
+// delivers one final Progress value and exits.
+func (u *Updater) run(counter Counter, size int64, d time.Duration, window int, alpha float64, cb func(Progress)) {
+	defer close(u.stopped)
+
+	unknown := size <= 0
+	doner, _ := counter.(Doner)
+	var (
+		started time.Time
+		samples = make([]sample, 0, window)
+		ewma    float64
+		ewmaSet bool
+	)
+	compute := func() Progress {
+		now := time.Now()
+		progress := Progress{
+			n:       float64(counter.N()),
+			size:    float64(size),
+			unknown: unknown,
+		}
+		if unknown && doner != nil {
+			progress.done = doner.Done()
+		}
+		if mc, ok := counter.(*MultiCounter); ok {
+			progress.perStream = mc.PerStream()
+		}
+		if started.IsZero() {
+			if progress.Started() {
+				started = now
+			}
+		} else {
+			progress.speedAvg = progress.n / now.Sub(started).Seconds()
+		}
+
+		samples = append(samples, sample{t: now, n: progress.n})
+		if len(samples) > window {
+			samples = samples[len(samples)-window:]
+		}
+		if oldest := samples[0]; len(samples) > 1 && now.After(oldest.t) {
+			instant := (progress.n - oldest.n) / now.Sub(oldest.t).Seconds()
+			if !ewmaSet {
+				ewma = instant
+				ewmaSet = true
+			} else {
+				ewma = alpha*instant + (1-alpha)*ewma
+			}
+			progress.speed = instant
+			if !unknown && ewma > 0 {
+				remaining := progress.size - progress.n
+				progress.estimated = now.Add(time.Duration(remaining / ewma * float64(time.Second)))
+			}
+		}
+		return progress
+	}
+
+	if d <= 0 {
+		// time.NewTicker panics on a non-positive interval; the baseline
+		// time.After-based loop instead just ticked immediately. Clamp to
+		// a small but non-busy-looping interval rather than the smallest
+		// possible duration, which would otherwise pin a CPU core for the
+		// whole operation.
+		d = minInterval
+	}
+	ticker := time.NewTicker(d)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-u.stop:
+			cb(compute())
+			return
+		case <-ticker.C:
+			progress := compute()
+			cb(progress)
+			if progress.Complete() {
+				return
+			}
+		}
+	}
+}
+
 // NewTicker gets a channel on which ticks of Progress are sent
 // at duration d intervals until the operation is complete at which point
 // the channel is closed.
 // The counter is either a Reader or Writer (or any type that can report its progress).
 // The size is the total number of expected bytes being read or written.
-// If the context cancels the operation, the channel is closed.
+// If size is <= 0, the total is treated as unknown: Percent returns -1,
+// Remaining and Estimated return zero values, and the operation is only
+// Complete once counter implements Doner and reports Done. Writer does
+// not implement Doner, so a Writer with an unknown size never becomes
+// Complete on its own; the caller must stop it explicitly.
+// If the context cancels the operation, one final Progress value is offered
+// on the channel before it is closed. That value is delivered only if a
+// receiver is waiting on the channel at that moment; otherwise it is dropped
+// and the channel is closed without it.
 func NewTicker(ctx context.Context, counter Counter, size int64, d time.Duration) <-chan Progress {
-	var (
-		started time.Time
-		ch      = make(chan Progress)
-	)
+	return NewTickerOptions(ctx, counter, size, d, Options{})
+}
+
+// NewTickerOptions is like NewTicker but allows the sliding window size and
+// EWMA smoothing factor used for Speed and Remaining/Estimated to be
+// configured.
+func NewTickerOptions(ctx context.Context, counter Counter, size int64, d time.Duration, opts Options) <-chan Progress {
+	ch := make(chan Progress)
+	// cb first tries a non-blocking send: if a receiver is already
+	// waiting on ch (the common case, including the final flush made by
+	// u.Done() below while a consumer is still ranging over ch), delivery
+	// is immediate and unconditional. Only if that fails - no receiver is
+	// currently ready - does it fall back to racing the send against
+	// ctx.Done(), so a receiver that has stopped reading (e.g. after ctx
+	// cancellation) can't leave this goroutine, and Updater.Done, parked
+	// forever. A plain select between the two cases here would instead
+	// coin-flip away the final value even with an active receiver, since
+	// by the time Done() runs ctx is already cancelled.
+	u := NewUpdaterOptions(counter, size, d, func(p Progress) {
+		select {
+		case ch <- p:
+			return
+		default:
+		}
+		select {
+		case ch <- p:
+		case <-ctx.Done():
+		}
+	}, opts)
 	go func() {
-		defer close(ch)
-		for {
-			select {
-			case <-ctx.Done():
-				// context has finished - exit
-				return
-			case <-time.After(d):
-				progress := Progress{
-					n:    float64(counter.N()),
-					size: float64(size),
-				}
-				if started.IsZero() {
-					if progress.Started() {
-						started = time.Now()
-					}
-				} else {
-					now := time.Now()
-					ratio := progress.n / progress.size
-					past := float64(now.Sub(started))
-					future := time.Duration(past / ratio)
-					progress.estimated = started.Add(future)
-				}
-				ch <- progress
-				if progress.Complete() {
-					return
-				}
-			}
+		select {
+		case <-ctx.Done():
+			u.Done()
+		case <-u.stopped:
 		}
+		close(ch)
 	}()
 	return ch
 }
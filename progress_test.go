@@ -0,0 +1,128 @@
+package progress
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeCounter reports a fixed N without needing a real Reader/Writer.
+type fakeCounter struct {
+	n int64
+}
+
+func (c *fakeCounter) N() int64 {
+	return atomic.LoadInt64(&c.n)
+}
+
+// TestNewTickerFinalFlushOnCancel reproduces the scenario from the review:
+// an actively-receiving consumer, with the context cancelled before the
+// first tick interval elapses, must still see a final Progress value
+// before the channel closes.
+func TestNewTickerFinalFlushOnCancel(t *testing.T) {
+	const trials = 200
+	for i := 0; i < trials; i++ {
+		counter := &fakeCounter{n: 42}
+		ctx, cancel := context.WithCancel(context.Background())
+
+		ch := NewTicker(ctx, counter, 100, time.Hour)
+		cancel()
+
+		got := 0
+		for range ch {
+			got++
+		}
+		if got == 0 {
+			t.Fatalf("trial %d: expected a final Progress value before close, got none", i)
+		}
+	}
+}
+
+// TestNewTickerClosesWhenConsumerStopsReceiving reproduces the review's
+// second scenario: a consumer using the classic
+// select { case p := <-ch: ...; case <-ctx.Done(): return } pattern reads
+// exactly one value and then stops receiving entirely, and the context is
+// then cancelled. NewTicker's internal goroutines must still notice and
+// exit - closing ch - rather than leak forever blocked trying to deliver
+// a value nobody will ever read.
+func TestNewTickerClosesWhenConsumerStopsReceiving(t *testing.T) {
+	counter := &fakeCounter{n: 10}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := NewTicker(ctx, counter, 100, 2*time.Millisecond)
+
+	select {
+	case <-ch:
+	case <-ctx.Done():
+	}
+	cancel()
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("NewTicker leaked: channel never closed after the consumer stopped receiving and ctx was cancelled")
+		}
+	}
+}
+
+// TestNewTickerNonPositiveInterval checks that a non-positive d is
+// tolerated rather than panicking inside the Updater's goroutine, as
+// time.NewTicker would do if handed d directly.
+func TestNewTickerNonPositiveInterval(t *testing.T) {
+	counter := &fakeCounter{n: 100}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := NewTicker(ctx, counter, 100, 0)
+	select {
+	case p, ok := <-ch:
+		if !ok {
+			t.Fatal("expected at least one Progress value before close")
+		}
+		if !p.Complete() {
+			t.Fatal("expected Complete once N reaches Size")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("NewTicker with d=0 never produced a tick")
+	}
+}
+
+// TestSpeedEWMA checks, with Window and Alpha configured through
+// NewTickerOptions, that Speed reports a positive sliding-window
+// instantaneous rate once the window holds more than one sample.
+func TestSpeedEWMA(t *testing.T) {
+	counter := &fakeCounter{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := NewTickerOptions(ctx, counter, 1000, 10*time.Millisecond, Options{Window: 3, Alpha: 0.5})
+
+	atomic.StoreInt64(&counter.n, 100)
+	<-ch // first sample: window has only one point, Speed is not yet defined
+
+	atomic.StoreInt64(&counter.n, 200)
+	p := <-ch
+	if p.Speed() == 0 {
+		t.Fatalf("expected non-zero Speed once a sample window has more than one point, got %d", p.Speed())
+	}
+
+	atomic.StoreInt64(&counter.n, 1000)
+	p = <-ch
+	if !p.Complete() {
+		t.Fatalf("expected Complete once N reaches Size")
+	}
+	if p.Speed() <= 0 {
+		t.Fatalf("expected positive Speed after a burst of throughput, got %d", p.Speed())
+	}
+
+	if _, ok := <-ch; ok {
+		t.Fatalf("expected channel to close once the operation completed")
+	}
+}
@@ -0,0 +1,83 @@
+package progress
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReaderCountsBytesAndSignalsDone(t *testing.T) {
+	const s = "Now that's what I call progress"
+	r := NewReader(strings.NewReader(s))
+
+	n, err := io.Copy(io.Discard, r)
+	if err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	if int(n) != len(s) {
+		t.Fatalf("copied %d bytes, want %d", n, len(s))
+	}
+	if got := r.N(); got != int64(len(s)) {
+		t.Fatalf("N() = %d, want %d", got, len(s))
+	}
+	if !r.Done() {
+		t.Fatalf("Done() = false after EOF, want true")
+	}
+}
+
+func TestWriterCountsBytes(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+
+	const s = "Now that's what I call progress"
+	if _, err := io.Copy(w, strings.NewReader(s)); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	if got := w.N(); got != int64(len(s)) {
+		t.Fatalf("N() = %d, want %d", got, len(s))
+	}
+}
+
+// TestReaderNConcurrentWithRead exercises N() being read from another
+// goroutine while Read is in progress, as NewTicker's Updater does.
+func TestReaderNConcurrentWithRead(t *testing.T) {
+	r := NewReader(strings.NewReader(strings.Repeat("x", 1<<16)))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		io.Copy(io.Discard, r)
+	}()
+
+	for i := 0; i < 100; i++ {
+		_ = r.N()
+	}
+	wg.Wait()
+}
+
+// TestNewTickerUnknownSize checks that a size <= 0 ticker reports -1
+// Percent and only completes once the Reader reports Done via EOF.
+func TestNewTickerUnknownSize(t *testing.T) {
+	r := NewReader(strings.NewReader(strings.Repeat("x", 100)))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := NewTicker(ctx, r, 0, 5*time.Millisecond)
+	go io.Copy(io.Discard, r)
+
+	var last Progress
+	for p := range ch {
+		if p.Percent() != -1 {
+			t.Fatalf("Percent() = %v, want -1 for unknown size", p.Percent())
+		}
+		last = p
+	}
+	if !last.Complete() {
+		t.Fatalf("expected the final tick to be Complete once the Reader hit EOF")
+	}
+}
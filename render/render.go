@@ -0,0 +1,201 @@
+// Package render draws a self-rewriting terminal progress bar from a
+// stream of progress.Progress values.
+//
+//	bar := render.NewBar(os.Stderr, 40)
+//	ch := progress.NewTicker(ctx, r, size, time.Second)
+//	bar.Run(ch)
+package render
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/OlegGorj/progress"
+)
+
+// defaultTemplate is the template used by a Bar until SetTemplate is
+// called. With a bar width of 6 it renders as e.g.
+// "[===   ] 50.0% (512B/1.0KiB) 100B/s ETA 5s".
+const defaultTemplate = "{{.Bar}} {{.Percent}}% ({{.N}}/{{.Size}}) {{.Speed}}/s ETA {{.ETA}}"
+
+// minRedraw is the minimum time between repaints of a TTY writer,
+// independent of how often Update is called, so a fast ticker interval
+// doesn't flood a slow terminal.
+const minRedraw = 100 * time.Millisecond
+
+// data is the value passed to a Bar's template.
+type data struct {
+	Bar     string
+	Percent string
+	N       string
+	Size    string
+	Speed   string
+	ETA     string
+}
+
+// Bar renders progress.Progress values as a single-line progress bar.
+// On a TTY writer it redraws in place using carriage returns; on a
+// non-TTY writer (a log file, a pipe) it instead writes one
+// newline-terminated line per update, since redrawing in place only
+// makes sense on a terminal.
+type Bar struct {
+	mu        sync.Mutex
+	w         io.Writer
+	width     int
+	tty       bool
+	tmpl      *template.Template
+	lastPaint time.Time
+	lastLine  string
+}
+
+// NewBar gets a Bar that renders to w with the given bar width, using
+// defaultTemplate. Width must be positive; a width <= 0 selects 40.
+func NewBar(w io.Writer, width int) *Bar {
+	if width <= 0 {
+		width = 40
+	}
+	b := &Bar{
+		w:     w,
+		width: width,
+		tty:   isTTY(w),
+	}
+	if err := b.SetTemplate(defaultTemplate); err != nil {
+		// defaultTemplate is a constant known to parse.
+		panic(err)
+	}
+	return b
+}
+
+// SetTemplate changes the text/template used to render each line. The
+// template is executed against a struct exposing Bar, Percent, N, Size,
+// Speed and ETA, all pre-formatted strings.
+func (b *Bar) SetTemplate(s string) error {
+	t, err := template.New("bar").Parse(s)
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.tmpl = t
+	b.mu.Unlock()
+	return nil
+}
+
+// Run reads Progress values from ch, rendering each through b, until ch
+// is closed.
+func (b *Bar) Run(ch <-chan progress.Progress) {
+	for p := range ch {
+		b.Update(p)
+	}
+}
+
+// Update renders p. It is suitable for use directly as the callback
+// passed to progress.NewUpdater. On a TTY writer, repaints are throttled
+// to at most once per minRedraw; the final, Complete tick is always
+// drawn.
+func (b *Bar) Update(p progress.Progress) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	complete := p.Complete()
+	if b.tty && !complete {
+		now := time.Now()
+		if now.Sub(b.lastPaint) < minRedraw {
+			return
+		}
+		b.lastPaint = now
+	}
+
+	line := b.render(p)
+	if b.tty {
+		fmt.Fprintf(b.w, "\r%s", overwrite(line, b.lastLine))
+		b.lastLine = line
+		if complete {
+			fmt.Fprintln(b.w)
+		}
+		return
+	}
+	fmt.Fprintln(b.w, line)
+}
+
+// render executes b's template against p, formatting bytes and durations
+// for human consumption.
+func (b *Bar) render(p progress.Progress) string {
+	percent := p.Percent()
+	filled := 0
+	percentStr := "?"
+	if percent >= 0 {
+		filled = int(float64(b.width) * percent / 100)
+		if filled > b.width {
+			filled = b.width
+		}
+		percentStr = fmt.Sprintf("%.1f", percent)
+	}
+
+	eta := "?"
+	if !p.Complete() {
+		if remaining := p.Remaining(); remaining > 0 {
+			eta = remaining.Truncate(time.Second).String()
+		}
+	}
+
+	d := data{
+		Bar:     "[" + strings.Repeat("=", filled) + strings.Repeat(" ", b.width-filled) + "]",
+		Percent: percentStr,
+		N:       humanizeBytes(p.N()),
+		Size:    humanizeBytes(p.Size()),
+		Speed:   humanizeBytes(p.Speed()),
+		ETA:     eta,
+	}
+
+	var sb strings.Builder
+	// An error from Execute (e.g. a template set via SetTemplate that names
+	// a field data does not have) is ignored; the line is then whatever the
+	// template wrote before the error.
+	_ = b.tmpl.Execute(&sb, d)
+	return sb.String()
+}
+
+// overwrite pads line with trailing spaces so it fully overwrites prev
+// when redrawn with a carriage return, erasing any leftover characters
+// from a longer previous line.
+func overwrite(line, prev string) string {
+	if len(line) >= len(prev) {
+		return line
+	}
+	return line + strings.Repeat(" ", len(prev)-len(line))
+}
+
+// humanizeBytes formats n as a human-readable byte count using binary
+// (KiB/MiB/GiB) units.
+func humanizeBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// isTTY reports whether w is a terminal that supports carriage-return
+// redraws, as opposed to a plain file or pipe where status lines should
+// instead be written one per line.
+func isTTY(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
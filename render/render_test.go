@@ -0,0 +1,60 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OlegGorj/progress"
+)
+
+func TestBarRendersNonTTYOneLinePerUpdate(t *testing.T) {
+	r := progress.NewReader(strings.NewReader(strings.Repeat("x", 100)))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := progress.NewTicker(ctx, r, 100, 5*time.Millisecond)
+	go func() {
+		buf := make([]byte, 10)
+		for {
+			if _, err := r.Read(buf); err != nil {
+				return
+			}
+		}
+	}()
+
+	var out bytes.Buffer
+	bar := NewBar(&out, 10)
+	bar.Run(ch)
+
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if len(lines) == 0 || lines[0] == "" {
+		t.Fatalf("expected at least one rendered line, got %q", out.String())
+	}
+	last := lines[len(lines)-1]
+	if !strings.Contains(last, "100.0%") {
+		t.Fatalf("last line %q does not report 100%% completion", last)
+	}
+	if strings.Contains(out.String(), "\r") {
+		t.Fatalf("non-TTY writer should not receive carriage-return redraws, got %q", out.String())
+	}
+}
+
+func TestBarSetTemplate(t *testing.T) {
+	var out bytes.Buffer
+	bar := NewBar(&out, 5)
+	if err := bar.SetTemplate("{{.Percent}}%"); err != nil {
+		t.Fatalf("SetTemplate: %v", err)
+	}
+
+	r := progress.NewReader(strings.NewReader(strings.Repeat("x", 10)))
+	r.Read(make([]byte, 10))
+	u := progress.NewUpdater(r, 10, time.Hour, bar.Update)
+	u.Done()
+
+	if got := out.String(); got != "100.0%\n" {
+		t.Fatalf("rendered %q, want %q", got, "100.0%\n")
+	}
+}